@@ -1,7 +1,10 @@
 package gameserver
 
 import (
+	"strings"
+
 	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 type IngressRoutingMode string
@@ -9,6 +12,7 @@ type IngressRoutingMode string
 const (
 	IngressRoutingModeDomain IngressRoutingMode = "domain"
 	IngressRoutingModePath   IngressRoutingMode = "path"
+	IngressRoutingModeL4     IngressRoutingMode = "l4"
 
 	OctopsAnnotationIngressMode   = "octops.io/gameserver-ingress-mode"
 	OctopsAnnotationIngressDomain = "octops.io/gameserver-ingress-domain"
@@ -18,14 +22,116 @@ const (
 	OctopsAnnotationIssuerName    = "octops.io/issuer-tls-name"
 	OctopsAnnotationCustomPrefix  = "octops-"
 
+	OctopsAnnotationL4Protocol          = "octops.io/l4-protocol"
+	OctopsAnnotationL4ExternalPortRange = "octops.io/l4-external-port-range"
+	OctopsAnnotationL4IngressController = "octops.io/l4-ingress-controller"
+
+	OctopsAnnotationTLSMinVersion         = "octops.io/tls-min-version"
+	OctopsAnnotationTLSCipherSuites       = "octops.io/tls-cipher-suites"
+	OctopsAnnotationSSLRedirect           = "octops.io/ssl-redirect"
+	OctopsAnnotationHSTSMaxAge            = "octops.io/hsts-max-age"
+	OctopsAnnotationHSTSIncludeSubdomains = "octops.io/hsts-include-subdomains"
+	OctopsAnnotationIngressClass          = "octops.io/ingress-class"
+
+	OctopsAnnotationPathRewriteTarget = "octops.io/path-rewrite-target"
+	OctopsAnnotationPathTemplate      = "octops.io/path-template"
+
+	OctopsAnnotationWhitelistSourceRange = "octops.io/whitelist-source-range"
+	OctopsAnnotationAuthURL              = "octops.io/auth-url"
+	OctopsAnnotationAuthSignin           = "octops.io/auth-signin"
+	OctopsAnnotationAuthResponseHeaders  = "octops.io/auth-response-headers"
+	OctopsAnnotationAuthMethod           = "octops.io/auth-method"
+
+	OctopsAnnotationTLSMode       = "octops.io/tls-mode"
+	OctopsAnnotationTLSGroup      = "octops.io/tls-group"
+	OctopsAnnotationTLSExtraHosts = "octops.io/tls-extra-hosts"
+
 	CertManagerAnnotationIssuer = "cert-manager.io/issuer"
 	AgonesGameServerNameLabel   = "agones.dev/gameserver"
+
+	L4IngressControllerNginx   = "nginx"
+	L4IngressControllerTraefik = "traefik"
+	L4IngressControllerHAProxy = "haproxy"
+
+	IngressControllerFlavorNginx   = "nginx"
+	IngressControllerFlavorTraefik = "traefik"
+
+	TLSVersion12 = "TLSv1.2"
+	TLSVersion13 = "TLSv1.3"
 )
 
+// AllowedTLSVersions is the allow-list enforced on octops.io/tls-min-version so an
+// invalid value is rejected at reconcile time instead of being passed through to the
+// ingress controller's data plane.
+var AllowedTLSVersions = map[string]bool{
+	TLSVersion12: true,
+	TLSVersion13: true,
+}
+
+// AllowedTLSCipherSuites is the allow-list enforced on octops.io/tls-cipher-suites.
+// It covers the OpenSSL cipher names commonly accepted by nginx-ingress and Traefik.
+var AllowedTLSCipherSuites = map[string]bool{
+	"ECDHE-ECDSA-AES128-GCM-SHA256": true,
+	"ECDHE-RSA-AES128-GCM-SHA256":   true,
+	"ECDHE-ECDSA-AES256-GCM-SHA384": true,
+	"ECDHE-RSA-AES256-GCM-SHA384":   true,
+	"ECDHE-ECDSA-CHACHA20-POLY1305": true,
+	"ECDHE-RSA-CHACHA20-POLY1305":   true,
+	"TLS_AES_128_GCM_SHA256":        true,
+	"TLS_AES_256_GCM_SHA384":        true,
+	"TLS_CHACHA20_POLY1305_SHA256":  true,
+}
+
 func (m IngressRoutingMode) String() string {
 	return string(m)
 }
 
+// TLSMode selects how WithTLS provisions certificates for a GameServer.
+type TLSMode string
+
+const (
+	// TLSModePerServer is the default: one Certificate/Secret per GameServer.
+	TLSModePerServer TLSMode = "per-server"
+	// TLSModeShared groups GameServers (by octops.io/tls-group, or the ingress domain)
+	// behind a single Certificate/Secret listing every member as a SAN.
+	TLSModeShared TLSMode = "shared"
+	// TLSModeWildcard points every GameServer in a domain at one pre-provisioned
+	// wildcard Secret instead of issuing per-server or per-group certificates.
+	TLSModeWildcard TLSMode = "wildcard"
+)
+
+func (m TLSMode) String() string {
+	return string(m)
+}
+
+// GetTLSMode returns the TLS provisioning strategy selected via octops.io/tls-mode,
+// defaulting to TLSModePerServer.
+func GetTLSMode(gs *agonesv1.GameServer) TLSMode {
+	if mode, ok := HasAnnotation(gs, OctopsAnnotationTLSMode); ok {
+		return TLSMode(mode)
+	}
+
+	return TLSModePerServer
+}
+
+// GetTLSExtraHosts returns the extra SANs configured via octops.io/tls-extra-hosts, to
+// be merged into IngressTLS.Hosts alongside whatever WithTLS computes as the primary host.
+func GetTLSExtraHosts(gs *agonesv1.GameServer) []string {
+	value, ok := HasAnnotation(gs, OctopsAnnotationTLSExtraHosts)
+	if !ok || len(value) == 0 {
+		return nil
+	}
+
+	var hosts []string
+	for _, host := range strings.Split(value, ",") {
+		if host = strings.TrimSpace(host); len(host) > 0 {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
 func FromObject(obj interface{}) *agonesv1.GameServer {
 	if gs, ok := obj.(*agonesv1.GameServer); ok {
 		return gs
@@ -35,13 +141,52 @@ func FromObject(obj interface{}) *agonesv1.GameServer {
 }
 
 func GetGameServerPort(gs *agonesv1.GameServer) agonesv1.GameServerStatusPort {
-	if len(gs.Status.Ports) > 0 {
-		return gs.Status.Ports[0]
+	ports := GetGameServerPorts(gs)
+	if len(ports) > 0 {
+		return ports[0]
 	}
 
 	return agonesv1.GameServerStatusPort{}
 }
 
+// GetGameServerPorts returns every port allocated to the GameServer. It backs the
+// L4 reconciler, which unlike the Ingress reconciler needs to expose all of them,
+// not just the first one.
+func GetGameServerPorts(gs *agonesv1.GameServer) []agonesv1.GameServerStatusPort {
+	return gs.Status.Ports
+}
+
+// GetGameServerPortProtocol returns the protocol (UDP/TCP/SCTP) configured on the
+// GameServer spec for the given status port, matching them by name. The
+// octops.io/l4-protocol annotation, when present, overrides the spec value for all
+// ports so operators can force a protocol without touching the Fleet manifest.
+func GetGameServerPortProtocol(gs *agonesv1.GameServer, port agonesv1.GameServerStatusPort) corev1.Protocol {
+	if value, ok := HasAnnotation(gs, OctopsAnnotationL4Protocol); ok && len(value) > 0 {
+		return corev1.Protocol(value)
+	}
+
+	for _, specPort := range gs.Spec.Ports {
+		if specPort.Name == port.Name {
+			if len(specPort.Protocol) > 0 {
+				return specPort.Protocol
+			}
+			break
+		}
+	}
+
+	return corev1.ProtocolUDP
+}
+
+// GetL4IngressController returns the L4 backend flavor (nginx, traefik or haproxy)
+// selected for the GameServer via octops.io/l4-ingress-controller, defaulting to nginx.
+func GetL4IngressController(gs *agonesv1.GameServer) string {
+	if value, ok := HasAnnotation(gs, OctopsAnnotationL4IngressController); ok && len(value) > 0 {
+		return value
+	}
+
+	return L4IngressControllerNginx
+}
+
 func GetGameServerContainerPort(gs *agonesv1.GameServer) int32 {
 	if len(gs.Spec.Ports) > 0 {
 		return gs.Spec.Ports[0].ContainerPort
@@ -50,6 +195,20 @@ func GetGameServerContainerPort(gs *agonesv1.GameServer) int32 {
 	return 0
 }
 
+// GetGameServerContainerPortByName returns the container port configured on the
+// GameServer spec for the given port name, matching it against gs.Status.Ports the
+// same way GetGameServerPortProtocol does. It backs the L4 reconciler, which must map
+// every status port to its own container port rather than always the first one.
+func GetGameServerContainerPortByName(gs *agonesv1.GameServer, name string) int32 {
+	for _, specPort := range gs.Spec.Ports {
+		if specPort.Name == name {
+			return specPort.ContainerPort
+		}
+	}
+
+	return 0
+}
+
 func HasAnnotation(gs *agonesv1.GameServer, annotation string) (string, bool) {
 	if value, ok := gs.Annotations[annotation]; ok {
 		return value, true
@@ -81,3 +240,19 @@ func GetTLSCertIssuer(gs *agonesv1.GameServer) string {
 
 	return ""
 }
+
+// GetIngressClassFlavor returns the ingress-controller flavor (nginx or traefik) used
+// to translate TLS policy and security header options into controller-specific
+// annotations. The per-GameServer octops.io/ingress-class annotation takes precedence
+// over the --ingress-class-flavor flag value passed in as defaultFlavor.
+func GetIngressClassFlavor(gs *agonesv1.GameServer, defaultFlavor string) string {
+	if value, ok := HasAnnotation(gs, OctopsAnnotationIngressClass); ok && len(value) > 0 {
+		return value
+	}
+
+	if len(defaultFlavor) == 0 {
+		return IngressControllerFlavorNginx
+	}
+
+	return defaultFlavor
+}
@@ -0,0 +1,300 @@
+package gameserver
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	ReasonInvalidAnnotation = "InvalidAnnotation"
+)
+
+// ValidationError describes a single annotation that failed validation. It is returned
+// by ValidateGameServer so callers can report exactly which annotation and value caused
+// a Fleet or GameServer to be rejected, instead of surfacing a generic reconcile error.
+type ValidationError struct {
+	Annotation string
+	Value      string
+	Reason     string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("annotation %s=%q is invalid: %s", e.Annotation, e.Value, e.Reason)
+}
+
+// annotationDescriptor describes how a single Octops annotation should be validated.
+// requiredWith lets an annotation be mandatory only when another annotation has a
+// given value, e.g. gameserver-ingress-domain is only required when the ingress mode
+// is "domain".
+type annotationDescriptor struct {
+	requiredWith func(gs *agonesv1.GameServer) bool
+	validate     func(gs *agonesv1.GameServer, value string) error
+}
+
+var rfc1123SubdomainRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+var dns1123LabelRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+var annotationKeyRegex = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+
+// annotationRegistry maps an Octops annotation to the rules that decide whether its
+// value is acceptable. ValidateGameServer walks this registry rather than validating
+// ad-hoc inside every IngressOption, so a bad value is rejected once, up front.
+var annotationRegistry = map[string]annotationDescriptor{
+	OctopsAnnotationIngressMode: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			switch IngressRoutingMode(value) {
+			case IngressRoutingModeDomain, IngressRoutingModePath, IngressRoutingModeL4:
+				return nil
+			default:
+				return fmt.Errorf("must be one of %q, %q or %q", IngressRoutingModeDomain, IngressRoutingModePath, IngressRoutingModeL4)
+			}
+		},
+	},
+	OctopsAnnotationIngressDomain: {
+		requiredWith: func(gs *agonesv1.GameServer) bool {
+			return GetIngressRoutingMode(gs) == IngressRoutingModeDomain
+		},
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			if !rfc1123SubdomainRegex.MatchString(value) {
+				return fmt.Errorf("must be a valid RFC-1123 subdomain")
+			}
+			return nil
+		},
+	},
+	OctopsAnnotationIngressFQDN: {
+		requiredWith: func(gs *agonesv1.GameServer) bool {
+			return GetIngressRoutingMode(gs) == IngressRoutingModePath
+		},
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			if !rfc1123SubdomainRegex.MatchString(value) {
+				return fmt.Errorf("must be a valid DNS name")
+			}
+			return nil
+		},
+	},
+	OctopsAnnotationTerminateTLS: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			if value != "true" && value != "false" {
+				return fmt.Errorf("must be \"true\" or \"false\"")
+			}
+			return nil
+		},
+	},
+	OctopsAnnotationSecretName: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			if len(value) > 63 || !dns1123LabelRegex.MatchString(value) {
+				return fmt.Errorf("must be a valid DNS-1123 label")
+			}
+			return nil
+		},
+	},
+	OctopsAnnotationIssuerName: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			if len(strings.TrimSpace(value)) == 0 {
+				return fmt.Errorf("must not be empty")
+			}
+			return nil
+		},
+	},
+	OctopsAnnotationTLSMinVersion: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			if !AllowedTLSVersions[value] {
+				return fmt.Errorf("must be one of %q or %q", TLSVersion12, TLSVersion13)
+			}
+			return nil
+		},
+	},
+	OctopsAnnotationTLSCipherSuites: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			for _, cipher := range strings.Split(value, ",") {
+				cipher = strings.TrimSpace(cipher)
+				if !AllowedTLSCipherSuites[cipher] {
+					return fmt.Errorf("cipher suite %q is not in the allow-list", cipher)
+				}
+			}
+			return nil
+		},
+	},
+	OctopsAnnotationSSLRedirect: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			if value != "true" && value != "false" {
+				return fmt.Errorf("must be \"true\" or \"false\"")
+			}
+			return nil
+		},
+	},
+	OctopsAnnotationHSTSMaxAge: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			if _, err := strconv.ParseUint(value, 10, 32); err != nil {
+				return fmt.Errorf("must be a non-negative integer")
+			}
+			return nil
+		},
+	},
+	OctopsAnnotationHSTSIncludeSubdomains: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			if value != "true" && value != "false" {
+				return fmt.Errorf("must be \"true\" or \"false\"")
+			}
+			return nil
+		},
+	},
+	OctopsAnnotationIngressClass: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			switch value {
+			case IngressControllerFlavorNginx, IngressControllerFlavorTraefik:
+				return nil
+			default:
+				return fmt.Errorf("must be one of %q or %q", IngressControllerFlavorNginx, IngressControllerFlavorTraefik)
+			}
+		},
+	},
+	OctopsAnnotationWhitelistSourceRange: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			for _, cidr := range strings.Split(value, ",") {
+				if _, _, err := net.ParseCIDR(strings.TrimSpace(cidr)); err != nil {
+					return fmt.Errorf("contains an invalid CIDR %q", cidr)
+				}
+			}
+			return nil
+		},
+	},
+	OctopsAnnotationAuthURL: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			u, err := url.Parse(value)
+			if err != nil || u.Scheme != "https" {
+				return fmt.Errorf("must be an https URL")
+			}
+			return nil
+		},
+	},
+	OctopsAnnotationL4ExternalPortRange: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			parts := strings.SplitN(value, "-", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("must be in the format <start>-<end>")
+			}
+
+			start, err := strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 32)
+			if err != nil {
+				return fmt.Errorf("has an invalid range start: %v", err)
+			}
+
+			end, err := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 32)
+			if err != nil {
+				return fmt.Errorf("has an invalid range end: %v", err)
+			}
+
+			if end < start {
+				return fmt.Errorf("has an end port lower than its start port")
+			}
+
+			return nil
+		},
+	},
+	OctopsAnnotationTLSMode: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			switch TLSMode(value) {
+			case TLSModePerServer, TLSModeShared, TLSModeWildcard:
+				return nil
+			default:
+				return fmt.Errorf("must be one of %q, %q or %q", TLSModePerServer, TLSModeShared, TLSModeWildcard)
+			}
+		},
+	},
+	OctopsAnnotationAuthMethod: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			switch strings.ToUpper(value) {
+			case "GET", "POST", "HEAD":
+				return nil
+			default:
+				return fmt.Errorf("must be one of \"GET\", \"POST\" or \"HEAD\"")
+			}
+		},
+	},
+	OctopsAnnotationL4Protocol: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			switch corev1.Protocol(value) {
+			case corev1.ProtocolUDP, corev1.ProtocolTCP, corev1.ProtocolSCTP:
+				return nil
+			default:
+				return fmt.Errorf("must be one of %q, %q or %q", corev1.ProtocolUDP, corev1.ProtocolTCP, corev1.ProtocolSCTP)
+			}
+		},
+	},
+	OctopsAnnotationL4IngressController: {
+		validate: func(gs *agonesv1.GameServer, value string) error {
+			switch value {
+			case L4IngressControllerNginx, L4IngressControllerTraefik, L4IngressControllerHAProxy:
+				return nil
+			default:
+				return fmt.Errorf("must be one of %q, %q or %q", L4IngressControllerNginx, L4IngressControllerTraefik, L4IngressControllerHAProxy)
+			}
+		},
+	},
+}
+
+// ValidateGameServer checks every Octops annotation present on gs against
+// annotationRegistry, plus the generic octops- passthrough prefix, and returns one
+// ValidationError per violation. It should be called before any IngressOption runs so
+// a misconfigured Fleet fails fast with a clear, actionable message.
+func ValidateGameServer(gs *agonesv1.GameServer) []*ValidationError {
+	var violations []*ValidationError
+
+	for annotation, descriptor := range annotationRegistry {
+		value, ok := HasAnnotation(gs, annotation)
+		if !ok {
+			if descriptor.requiredWith != nil && descriptor.requiredWith(gs) {
+				violations = append(violations, &ValidationError{
+					Annotation: annotation,
+					Reason:     "is required for this ingress routing mode but is not set",
+				})
+			}
+			continue
+		}
+
+		if descriptor.validate == nil {
+			continue
+		}
+
+		if err := descriptor.validate(gs, value); err != nil {
+			violations = append(violations, &ValidationError{
+				Annotation: annotation,
+				Value:      value,
+				Reason:     err.Error(),
+			})
+		}
+	}
+
+	for annotation, value := range gs.Annotations {
+		if !strings.HasPrefix(annotation, OctopsAnnotationCustomPrefix) {
+			continue
+		}
+
+		suffix := strings.TrimPrefix(annotation, OctopsAnnotationCustomPrefix)
+		if len(suffix) == 0 || !annotationKeyRegex.MatchString(suffix) {
+			violations = append(violations, &ValidationError{
+				Annotation: annotation,
+				Value:      value,
+				Reason:     "suffix after the octops- prefix must be a legal annotation key",
+			})
+		}
+	}
+
+	return violations
+}
+
+// RecordValidationEvents emits a Warning Event per violation on gs, so a misconfigured
+// Fleet is diagnosable from `kubectl describe gameserver` without reading controller logs.
+func RecordValidationEvents(recorder record.EventRecorder, gs *agonesv1.GameServer, violations []*ValidationError) {
+	for _, v := range violations {
+		recorder.Event(gs, corev1.EventTypeWarning, ReasonInvalidAnnotation, v.Error())
+	}
+}
@@ -0,0 +1,111 @@
+package reconcilers
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	"github.com/Octops/gameserver-ingress-controller/pkg/gameserver"
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+const (
+	nginxAnnotationWhitelistSourceRange = "nginx.ingress.kubernetes.io/whitelist-source-range"
+
+	nginxAnnotationAuthURL             = "nginx.ingress.kubernetes.io/auth-url"
+	nginxAnnotationAuthSignin          = "nginx.ingress.kubernetes.io/auth-signin"
+	nginxAnnotationAuthResponseHeaders = "nginx.ingress.kubernetes.io/auth-response-headers"
+	nginxAnnotationAuthMethod          = "nginx.ingress.kubernetes.io/auth-method"
+)
+
+// WithWhitelistSourceRange restricts who can reach the GameServer at the ingress layer,
+// driven by the comma-separated CIDRs in octops.io/whitelist-source-range. Each CIDR is
+// parsed and normalized so equivalent inputs ("10.0.0.0/24" vs "10.0.0.1/24") always
+// produce the same annotation value.
+func WithWhitelistSourceRange(defaultFlavor string) IngressOption {
+	return func(gs *agonesv1.GameServer, ingress *networkingv1.Ingress) error {
+		value, ok := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationWhitelistSourceRange)
+		if !ok || len(value) == 0 {
+			return nil
+		}
+
+		cidrs := make([]string, 0)
+		for _, raw := range strings.Split(value, ",") {
+			raw = strings.TrimSpace(raw)
+			if len(raw) == 0 {
+				continue
+			}
+
+			_, network, err := net.ParseCIDR(raw)
+			if err != nil {
+				return errors.Errorf("annotation %s for %s contains an invalid CIDR %q: %v", gameserver.OctopsAnnotationWhitelistSourceRange, gs.Name, raw, err)
+			}
+
+			cidrs = append(cidrs, network.String())
+		}
+
+		sort.Strings(cidrs)
+
+		switch gameserver.GetIngressClassFlavor(gs, defaultFlavor) {
+		case gameserver.IngressControllerFlavorTraefik:
+			// Traefik enforces IP allow-lists through an ipWhiteList Middleware object,
+			// which this option has no way to create or update from here. Emitting a
+			// static router.middlewares reference would point at a Middleware that may
+			// not exist or may not carry these CIDRs, silently failing to enforce the
+			// allow-list, so we refuse instead.
+			return errors.Errorf("annotation %s for %s is not supported for the traefik ingress class: provision an ipWhiteList Middleware for this GameServer and reference it directly instead", gameserver.OctopsAnnotationWhitelistSourceRange, gs.Name)
+		case gameserver.IngressControllerFlavorNginx:
+			fallthrough
+		default:
+			ingress.Annotations[nginxAnnotationWhitelistSourceRange] = strings.Join(cidrs, ",")
+		}
+
+		return nil
+	}
+}
+
+// WithForwardAuth configures an external authentication check (e.g. a matchmaker token
+// check) in front of the GameServer, driven by octops.io/auth-url, octops.io/auth-signin,
+// octops.io/auth-response-headers and octops.io/auth-method. octops.io/auth-url must use
+// https so the token is never sent to the auth service in the clear.
+func WithForwardAuth(defaultFlavor string) IngressOption {
+	return func(gs *agonesv1.GameServer, ingress *networkingv1.Ingress) error {
+		authURL, ok := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationAuthURL)
+		if !ok || len(authURL) == 0 {
+			return nil
+		}
+
+		parsed, err := url.Parse(authURL)
+		if err != nil || parsed.Scheme != "https" {
+			return errors.Errorf("annotation %s for %s must be an https URL", gameserver.OctopsAnnotationAuthURL, gs.Name)
+		}
+
+		switch gameserver.GetIngressClassFlavor(gs, defaultFlavor) {
+		case gameserver.IngressControllerFlavorTraefik:
+			// Traefik enforces forward auth through a forwardAuth Middleware object,
+			// which this option has no way to create or update from here. Emitting a
+			// static router.middlewares reference would point at a Middleware that may
+			// not exist or may not carry this auth-url, silently failing to enforce the
+			// check, so we refuse instead.
+			return errors.Errorf("annotation %s for %s is not supported for the traefik ingress class: provision a forwardAuth Middleware for this GameServer and reference it directly instead", gameserver.OctopsAnnotationAuthURL, gs.Name)
+		case gameserver.IngressControllerFlavorNginx:
+			fallthrough
+		default:
+			ingress.Annotations[nginxAnnotationAuthURL] = authURL
+			if signin, ok := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationAuthSignin); ok {
+				ingress.Annotations[nginxAnnotationAuthSignin] = signin
+			}
+			if headers, ok := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationAuthResponseHeaders); ok {
+				ingress.Annotations[nginxAnnotationAuthResponseHeaders] = headers
+			}
+			if method, ok := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationAuthMethod); ok {
+				ingress.Annotations[nginxAnnotationAuthMethod] = method
+			}
+		}
+
+		return nil
+	}
+}
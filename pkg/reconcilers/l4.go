@@ -0,0 +1,163 @@
+package reconcilers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	"github.com/Octops/gameserver-ingress-controller/pkg/gameserver"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// L4ServiceOption mirrors IngressOption but operates on the Service generated for
+// IngressRoutingModeL4, since L4 traffic is routed via a Service rather than an Ingress.
+type L4ServiceOption func(gs *agonesv1.GameServer, svc *corev1.Service) error
+
+// NewL4Service builds the Service that exposes every port in gs.Status.Ports, applying
+// the given L4ServiceOptions on top of the defaults.
+func NewL4Service(gs *agonesv1.GameServer, opts ...L4ServiceOption) (*corev1.Service, error) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gs.Name,
+			Namespace: gs.Namespace,
+			Labels: map[string]string{
+				gameserver.AgonesGameServerNameLabel: gs.Name,
+			},
+			Annotations: map[string]string{},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{
+				gameserver.AgonesGameServerNameLabel: gs.Name,
+			},
+		},
+	}
+
+	ports, err := newL4ServicePorts(gs)
+	if err != nil {
+		return nil, err
+	}
+	svc.Spec.Ports = ports
+
+	for _, opt := range opts {
+		if err := opt(gs, svc); err != nil {
+			return nil, err
+		}
+	}
+
+	return svc, nil
+}
+
+func newL4ServicePorts(gs *agonesv1.GameServer) ([]corev1.ServicePort, error) {
+	ports := gameserver.GetGameServerPorts(gs)
+	start, end, hasRange, err := parseExternalPortRange(gs)
+	if err != nil {
+		return nil, err
+	}
+
+	servicePorts := make([]corev1.ServicePort, 0, len(ports))
+	for i, port := range ports {
+		externalPort := port.Port
+		if hasRange && int32(i) <= end-start {
+			externalPort = start + int32(i)
+		}
+
+		servicePorts = append(servicePorts, corev1.ServicePort{
+			Name:       port.Name,
+			Protocol:   gameserver.GetGameServerPortProtocol(gs, port),
+			Port:       externalPort,
+			TargetPort: intstr.FromInt(int(gameserver.GetGameServerContainerPortByName(gs, port.Name))),
+		})
+	}
+
+	return servicePorts, nil
+}
+
+func parseExternalPortRange(gs *agonesv1.GameServer) (start, end int32, ok bool, err error) {
+	value, has := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationL4ExternalPortRange)
+	if !has || len(value) == 0 {
+		return 0, 0, false, nil
+	}
+
+	parts := strings.SplitN(value, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, false, errors.Errorf("annotation %s for %s must be in the format <start>-<end>", gameserver.OctopsAnnotationL4ExternalPortRange, gs.Name)
+	}
+
+	startPort, err := strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 32)
+	if err != nil {
+		return 0, 0, false, errors.Errorf("annotation %s for %s has an invalid range start: %v", gameserver.OctopsAnnotationL4ExternalPortRange, gs.Name, err)
+	}
+
+	endPort, err := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 32)
+	if err != nil {
+		return 0, 0, false, errors.Errorf("annotation %s for %s has an invalid range end: %v", gameserver.OctopsAnnotationL4ExternalPortRange, gs.Name, err)
+	}
+
+	if endPort < startPort {
+		return 0, 0, false, errors.Errorf("annotation %s for %s has an end port lower than its start port", gameserver.OctopsAnnotationL4ExternalPortRange, gs.Name)
+	}
+
+	return int32(startPort), int32(endPort), true, nil
+}
+
+// WithL4Service sets the ServiceType (LoadBalancer or NodePort) on the generated Service.
+func WithL4Service(serviceType corev1.ServiceType) L4ServiceOption {
+	return func(gs *agonesv1.GameServer, svc *corev1.Service) error {
+		switch serviceType {
+		case corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeNodePort:
+			svc.Spec.Type = serviceType
+		default:
+			return errors.Errorf("l4 service for %s does not support type %s, must be LoadBalancer or NodePort", gs.Name, serviceType)
+		}
+
+		return nil
+	}
+}
+
+// L4ConfigMapEntry is a single "external-port -> namespace/svc:port" routing entry,
+// the format expected by the nginx-ingress tcp-services/udp-services ConfigMaps and
+// used as the basis for HAProxy frontend generation.
+type L4ConfigMapEntry struct {
+	ExternalPort int32
+	Protocol     corev1.Protocol
+	Value        string
+}
+
+// BuildL4ConfigMapEntries returns one entry per port in gs.Status.Ports, keyed by the
+// external port the GameServer is reachable on and pointing at namespace/svc:port.
+func BuildL4ConfigMapEntries(gs *agonesv1.GameServer, svc *corev1.Service) []L4ConfigMapEntry {
+	entries := make([]L4ConfigMapEntry, 0, len(svc.Spec.Ports))
+	for _, port := range svc.Spec.Ports {
+		entries = append(entries, L4ConfigMapEntry{
+			ExternalPort: port.Port,
+			Protocol:     port.Protocol,
+			Value:        fmt.Sprintf("%s/%s:%d", svc.Namespace, svc.Name, port.Port),
+		})
+	}
+
+	return entries
+}
+
+// ApplyL4ConfigMapEntries merges entries into cm, keeping only the ones matching
+// protocol. nginx-ingress keeps TCP and UDP routes in separate ConfigMaps
+// (tcp-services/udp-services), so the caller must pass the ConfigMap and protocol that
+// correspond to each other; entries for the other protocol are skipped rather than
+// colliding on the shared external-port key. cm is mutated in place so the caller can
+// Update() it unconditionally.
+func ApplyL4ConfigMapEntries(cm *corev1.ConfigMap, protocol corev1.Protocol, entries []L4ConfigMapEntry) {
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+
+	for _, entry := range entries {
+		if entry.Protocol != protocol {
+			continue
+		}
+
+		cm.Data[strconv.Itoa(int(entry.ExternalPort))] = entry.Value
+	}
+}
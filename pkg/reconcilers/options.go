@@ -31,7 +31,7 @@ func WithCustomAnnotations() IngressOption {
 	}
 }
 
-func WithTLS(mode gameserver.IngressRoutingMode) IngressOption {
+func WithTLS(mode gameserver.IngressRoutingMode, groupIndex *TLSGroupIndex) IngressOption {
 	return func(gs *agonesv1.GameServer, ingress *networkingv1.Ingress) error {
 		errMsgInvalidAnnotation := func(mode, annotation string) error {
 			return errors.Errorf("ingress routing mode %s requires the annotation %s to be set", mode, annotation)
@@ -76,11 +76,41 @@ func WithTLS(mode gameserver.IngressRoutingMode) IngressOption {
 			secret = specificsecret
 		}
 
+		hosts := []string{host}
+
+		switch gameserver.GetTLSMode(gs) {
+		case gameserver.TLSModeShared:
+			group := tlsGroupKey(gs)
+			if len(group) == 0 {
+				return errors.Errorf("tls-mode %s for %s requires either %s or %s to be set", gameserver.TLSModeShared, gs.Name, gameserver.OctopsAnnotationTLSGroup, gameserver.OctopsAnnotationIngressDomain)
+			}
+
+			if groupIndex != nil {
+				groupIndex.Update(gs, host)
+				hosts = groupIndex.Hosts(group)
+			}
+
+			secret = fmt.Sprintf("%s-tls", strings.ToLower(group))
+		case gameserver.TLSModeWildcard:
+			domain, ok := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationIngressDomain)
+			if !ok {
+				return errors.Errorf("tls-mode %s for %s requires %s to be set", gameserver.TLSModeWildcard, gs.Name, gameserver.OctopsAnnotationIngressDomain)
+			}
+
+			secretName, ok := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationSecretName)
+			if !ok {
+				return errors.Errorf("tls-mode %s for %s requires %s to reference a pre-provisioned wildcard secret", gameserver.TLSModeWildcard, gs.Name, gameserver.OctopsAnnotationSecretName)
+			}
+
+			hosts = []string{fmt.Sprintf("*.%s", domain)}
+			secret = secretName
+		}
+
+		hosts = append(hosts, gameserver.GetTLSExtraHosts(gs)...)
+
 		ingress.Spec.TLS = []networkingv1.IngressTLS{
 			{
-				Hosts: []string{
-					host,
-				},
+				Hosts:      hosts,
 				SecretName: secret,
 			},
 		}
@@ -103,7 +133,17 @@ func WithIngressRule(mode gameserver.IngressRoutingMode) IngressOption {
 			if !ok {
 				return errMsgInvalidAnnotation(mode.String(), gameserver.OctopsAnnotationIngressFQDN, gs.Name)
 			}
-			host, path = fqdn, "/"+gs.Name
+
+			path = "/" + gs.Name
+			if tmpl, ok := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationPathTemplate); ok && len(tmpl) > 0 {
+				rendered, err := renderPathTemplate(gs, tmpl)
+				if err != nil {
+					return err
+				}
+				path = rendered
+			}
+
+			host = fqdn
 		case gameserver.IngressRoutingModeDomain:
 			fallthrough
 		default:
@@ -121,6 +161,12 @@ func WithIngressRule(mode gameserver.IngressRoutingMode) IngressOption {
 
 func WithTLSCertIssuer(issuerName string) IngressOption {
 	return func(gs *agonesv1.GameServer, ingress *networkingv1.Ingress) error {
+		if gameserver.GetTLSMode(gs) == gameserver.TLSModeWildcard {
+			// Wildcard certificates are pre-provisioned out of band, so cert-manager
+			// must never be asked to issue one per GameServer.
+			return nil
+		}
+
 		terminate, ok := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationTerminateTLS)
 		if !ok || len(terminate) == 0 {
 			return nil
@@ -0,0 +1,72 @@
+package reconcilers
+
+import (
+	"bytes"
+	"net/url"
+	"text/template"
+
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	"github.com/Octops/gameserver-ingress-controller/pkg/gameserver"
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// pathTemplateData is the set of fields exposed to octops.io/path-template, letting
+// operators build paths such as /rooms/{{.Labels.region}}/{{.Name}}.
+type pathTemplateData struct {
+	Name        string
+	Namespace   string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// renderPathTemplate executes tmpl against gs and validates the result is a legal URL
+// path before it is ever written to ingress.Spec.Rules[].HTTP.Paths[].Path.
+func renderPathTemplate(gs *agonesv1.GameServer, tmpl string) (string, error) {
+	t, err := template.New("path-template").Parse(tmpl)
+	if err != nil {
+		return "", errors.Errorf("annotation %s for %s is not a valid template: %v", gameserver.OctopsAnnotationPathTemplate, gs.Name, err)
+	}
+
+	data := pathTemplateData{
+		Name:        gs.Name,
+		Namespace:   gs.Namespace,
+		Labels:      gs.Labels,
+		Annotations: gs.Annotations,
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", errors.Errorf("annotation %s for %s could not be rendered: %v", gameserver.OctopsAnnotationPathTemplate, gs.Name, err)
+	}
+
+	path := buf.String()
+	if u, err := url.Parse(path); err != nil || u.EscapedPath() != path || len(path) == 0 || path[0] != '/' {
+		return "", errors.Errorf("annotation %s for %s rendered an invalid URL path: %q", gameserver.OctopsAnnotationPathTemplate, gs.Name, path)
+	}
+
+	return path, nil
+}
+
+// WithPathRewrite sets the controller-specific rewrite-target annotation, driven by
+// octops.io/path-rewrite-target, so the GameServer receives the request without the
+// path prefix matched by the Ingress rule.
+func WithPathRewrite(defaultFlavor string) IngressOption {
+	return func(gs *agonesv1.GameServer, ingress *networkingv1.Ingress) error {
+		target, ok := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationPathRewriteTarget)
+		if !ok || len(target) == 0 {
+			return nil
+		}
+
+		switch gameserver.GetIngressClassFlavor(gs, defaultFlavor) {
+		case gameserver.IngressControllerFlavorTraefik:
+			ingress.Annotations["traefik.ingress.kubernetes.io/rewrite-target"] = target
+		case gameserver.IngressControllerFlavorNginx:
+			fallthrough
+		default:
+			ingress.Annotations["nginx.ingress.kubernetes.io/rewrite-target"] = target
+		}
+
+		return nil
+	}
+}
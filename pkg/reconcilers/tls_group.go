@@ -0,0 +1,83 @@
+package reconcilers
+
+import (
+	"sort"
+	"sync"
+
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	"github.com/Octops/gameserver-ingress-controller/pkg/gameserver"
+)
+
+// TLSGroupIndex tracks, for each TLS group (octops.io/tls-group, falling back to the
+// ingress domain), the host contributed by every GameServer currently reconciled into
+// it. WithTLS in TLSModeShared reads from this index so that adding or removing a
+// single GameServer updates the shared Ingress TLS block for every other member. It is
+// expected to be populated from the GameServer informer's AddFunc/UpdateFunc/DeleteFunc
+// alongside the normal reconcile loop.
+type TLSGroupIndex struct {
+	mu     sync.RWMutex
+	groups map[string]map[string]string // group -> "namespace/name" -> host
+}
+
+func NewTLSGroupIndex() *TLSGroupIndex {
+	return &TLSGroupIndex{
+		groups: map[string]map[string]string{},
+	}
+}
+
+func tlsGroupKey(gs *agonesv1.GameServer) string {
+	if group, ok := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationTLSGroup); ok && len(group) > 0 {
+		return group
+	}
+
+	domain, _ := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationIngressDomain)
+	return domain
+}
+
+func tlsGroupMemberKey(gs *agonesv1.GameServer) string {
+	return gs.Namespace + "/" + gs.Name
+}
+
+// Update adds or replaces the host contributed by gs to its TLS group.
+func (i *TLSGroupIndex) Update(gs *agonesv1.GameServer, host string) {
+	group := tlsGroupKey(gs)
+	if len(group) == 0 {
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.groups[group] == nil {
+		i.groups[group] = map[string]string{}
+	}
+	i.groups[group][tlsGroupMemberKey(gs)] = host
+}
+
+// Delete removes gs from its TLS group, e.g. once the GameServer has been deleted.
+func (i *TLSGroupIndex) Delete(gs *agonesv1.GameServer) {
+	group := tlsGroupKey(gs)
+	if len(group) == 0 {
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	delete(i.groups[group], tlsGroupMemberKey(gs))
+}
+
+// Hosts returns every host currently registered for group, sorted for stable output.
+func (i *TLSGroupIndex) Hosts(group string) []string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	members := i.groups[group]
+	hosts := make([]string, 0, len(members))
+	for _, host := range members {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	return hosts
+}
@@ -0,0 +1,121 @@
+package reconcilers
+
+import (
+	"strings"
+
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	"github.com/Octops/gameserver-ingress-controller/pkg/gameserver"
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+const (
+	nginxAnnotationSSLProtocols = "nginx.ingress.kubernetes.io/ssl-protocols"
+	nginxAnnotationSSLCiphers   = "nginx.ingress.kubernetes.io/ssl-ciphers"
+	nginxAnnotationSSLRedirect  = "nginx.ingress.kubernetes.io/ssl-redirect"
+	nginxAnnotationHSTS         = "nginx.ingress.kubernetes.io/hsts"
+	nginxAnnotationHSTSMaxAge   = "nginx.ingress.kubernetes.io/hsts-max-age"
+	nginxAnnotationHSTSSubdoms  = "nginx.ingress.kubernetes.io/hsts-include-subdomains"
+)
+
+// tlsVersionsFrom translates an octops.io/tls-min-version value into the set of TLS
+// protocol versions an ingress controller should still accept, e.g. TLSv1.2 implies
+// also accepting TLSv1.3.
+func tlsVersionsFrom(minVersion string) string {
+	switch minVersion {
+	case gameserver.TLSVersion13:
+		return gameserver.TLSVersion13
+	case gameserver.TLSVersion12:
+		fallthrough
+	default:
+		return strings.Join([]string{gameserver.TLSVersion12, gameserver.TLSVersion13}, " ")
+	}
+}
+
+// WithTLSPolicy applies the TLS minimum version and cipher suite configured via
+// octops.io/tls-min-version and octops.io/tls-cipher-suites to the Ingress, translating
+// them into the annotations understood by the ingress-controller flavor selected for
+// this GameServer (octops.io/ingress-class, falling back to defaultFlavor). Only the
+// nginx flavor is translated; for the traefik flavor it returns an error and leaves the
+// Ingress unchanged.
+func WithTLSPolicy(defaultFlavor string) IngressOption {
+	return func(gs *agonesv1.GameServer, ingress *networkingv1.Ingress) error {
+		minVersion, hasMinVersion := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationTLSMinVersion)
+		ciphers, hasCiphers := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationTLSCipherSuites)
+
+		if !hasMinVersion && !hasCiphers {
+			return nil
+		}
+
+		flavor := gameserver.GetIngressClassFlavor(gs, defaultFlavor)
+		if flavor == gameserver.IngressControllerFlavorTraefik {
+			// Traefik enforces TLS policy through a TLSOption object, which this option
+			// has no way to create or update from here. Emitting a static
+			// router.tls.options reference would point at a TLSOption that may not
+			// exist or may not carry this policy, silently failing to enforce it, so
+			// we refuse instead.
+			return errors.Errorf("annotations %s and %s for %s are not supported for the traefik ingress class: provision a TLSOption for this GameServer and reference it directly instead", gameserver.OctopsAnnotationTLSMinVersion, gameserver.OctopsAnnotationTLSCipherSuites, gs.Name)
+		}
+
+		if class, ok := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationIngressClass); ok && len(class) > 0 {
+			ingress.Spec.IngressClassName = &class
+		}
+
+		if hasMinVersion {
+			ingress.Annotations[nginxAnnotationSSLProtocols] = tlsVersionsFrom(minVersion)
+		}
+		if hasCiphers {
+			ingress.Annotations[nginxAnnotationSSLCiphers] = ciphers
+		}
+
+		return nil
+	}
+}
+
+// WithSecurityHeaders applies octops.io/ssl-redirect, octops.io/hsts-max-age and
+// octops.io/hsts-include-subdomains to the Ingress, translating them into the
+// annotations understood by the ingress-controller flavor selected for this GameServer.
+// Only the nginx flavor is translated; for the traefik flavor it returns an error and
+// leaves the Ingress unchanged.
+func WithSecurityHeaders(defaultFlavor string) IngressOption {
+	return func(gs *agonesv1.GameServer, ingress *networkingv1.Ingress) error {
+		redirect, hasRedirect := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationSSLRedirect)
+		maxAge, hasMaxAge := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationHSTSMaxAge)
+		subdomains, hasSubdomains := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationHSTSIncludeSubdomains)
+
+		if !hasRedirect && !hasMaxAge && !hasSubdomains {
+			return nil
+		}
+
+		flavor := gameserver.GetIngressClassFlavor(gs, defaultFlavor)
+		if flavor == gameserver.IngressControllerFlavorTraefik {
+			// Traefik enforces ssl-redirect and HSTS through headers/redirect-scheme
+			// Middleware objects, which this option has no way to create or update
+			// from here. Emitting a static router.middlewares reference would point
+			// at a Middleware that may not exist or may not carry this config,
+			// silently failing to enforce it, so we refuse instead of mutating
+			// anything on the Ingress.
+			return errors.Errorf("annotations %s, %s and %s for %s are not supported for the traefik ingress class: provision the redirect-scheme and headers Middlewares for this GameServer and reference them directly instead", gameserver.OctopsAnnotationSSLRedirect, gameserver.OctopsAnnotationHSTSMaxAge, gameserver.OctopsAnnotationHSTSIncludeSubdomains, gs.Name)
+		}
+
+		if class, ok := gameserver.HasAnnotation(gs, gameserver.OctopsAnnotationIngressClass); ok && len(class) > 0 {
+			ingress.Spec.IngressClassName = &class
+		}
+
+		if hasRedirect {
+			ingress.Annotations[nginxAnnotationSSLRedirect] = redirect
+		}
+
+		if hasMaxAge || hasSubdomains {
+			ingress.Annotations[nginxAnnotationHSTS] = "true"
+			if hasMaxAge {
+				ingress.Annotations[nginxAnnotationHSTSMaxAge] = maxAge
+			}
+			if hasSubdomains {
+				ingress.Annotations[nginxAnnotationHSTSSubdoms] = subdomains
+			}
+		}
+
+		return nil
+	}
+}